@@ -0,0 +1,139 @@
+// Package v1 holds the policy API types mutate.foreach is declared with.
+package v1
+
+// ResourceSpec identifies a Kubernetes resource.
+type ResourceSpec struct {
+	APIVersion string
+	Kind       string
+	Namespace  string
+	Name       string
+}
+
+// TargetResourceSpec is one entry of Mutation.Targets: an additional resource a mutate rule
+// patches besides the triggering resource.
+type TargetResourceSpec struct {
+	ResourceSpec
+}
+
+// Mutation is the body of a mutate rule.
+type Mutation struct {
+	Targets []TargetResourceSpec
+}
+
+// Rule is a single policy rule.
+type Rule struct {
+	Name     string
+	Mutation Mutation
+}
+
+// ContextEntry is one named value a rule or mutate.foreach block loads into its JSONContext
+// before evaluation (API calls, ConfigMap lookups, variables, etc.).
+type ContextEntry struct {
+	Name string
+}
+
+// ConditionOperator is the comparison a Condition applies between Key and Value.
+type ConditionOperator string
+
+const (
+	ConditionOperatorEquals    ConditionOperator = "Equals"
+	ConditionOperatorNotEquals ConditionOperator = "NotEquals"
+)
+
+// Condition is a single precondition check.
+type Condition struct {
+	Key      interface{}
+	Operator ConditionOperator
+	Value    interface{}
+}
+
+// AnyAllConditions groups preconditions the way a rule declares them: pass if any of
+// AnyConditions passes and all of AllConditions pass. Both empty means no preconditions.
+type AnyAllConditions struct {
+	AnyConditions []Condition
+	AllConditions []Condition
+}
+
+// Target is the desired end-state of a mutate.foreach element, used by PatchStrategy
+// TwoWayMerge as the diff target.
+type Target struct {
+	Object map[string]interface{}
+}
+
+// PatchStrategy selects how a mutate.foreach element's patch is computed.
+type PatchStrategy string
+
+const (
+	// TwoWayMerge diffs the element against foreach.RawTargetElement with a plain JSON merge
+	// patch (RFC 7396) and applies the result, instead of requiring the caller to author raw
+	// JSON/merge patch ops by hand. The diff is not a strategic merge: no schema is consulted,
+	// so list fields are replaced wholesale rather than merged by merge key.
+	TwoWayMerge PatchStrategy = "TwoWayMerge"
+)
+
+// ForEachErrorPolicy controls how mutate.foreach reacts to a failing element.
+type ForEachErrorPolicy string
+
+const (
+	// ErrorPolicyFail aborts the whole foreach block on the first element that errors or
+	// fails. This is the default, matching the pre-ErrorPolicy behaviour.
+	ErrorPolicyFail ForEachErrorPolicy = "Fail"
+	// ErrorPolicySkip continues processing the remaining elements, silently dropping ones
+	// that errored or failed (equivalent to the legacy ContinueOnError: true).
+	ErrorPolicySkip ForEachErrorPolicy = "Skip"
+	// ErrorPolicyCollect behaves like Skip but additionally records each failure as an
+	// ElementError surfaced on the rule response.
+	ErrorPolicyCollect ForEachErrorPolicy = "Collect"
+)
+
+// ConflictPolicy controls how mutate.foreach reconciles patches from different elements that
+// target the same JSON pointer path.
+type ConflictPolicy string
+
+const (
+	// ConflictPolicyFailOnConflict rejects the whole foreach block when two elements
+	// disagree on the same path. This is the default.
+	ConflictPolicyFailOnConflict ConflictPolicy = "FailOnConflict"
+	// ConflictPolicyLastWriterWins keeps the later element's operation.
+	ConflictPolicyLastWriterWins ConflictPolicy = "LastWriterWins"
+	// ConflictPolicyFirstWriterWins keeps the earlier element's operation.
+	ConflictPolicyFirstWriterWins ConflictPolicy = "FirstWriterWins"
+	// ConflictPolicyMerge combines the contending operations field-by-field instead of
+	// picking one outright. See mergeConflictingOps for exactly what "combines" means.
+	ConflictPolicyMerge ConflictPolicy = "Merge"
+)
+
+// ForEachMutation describes one mutate.foreach block.
+type ForEachMutation struct {
+	// List is the JMESPath expression evaluated to produce the elements to iterate.
+	List string
+	// Context lists additional values to load into each element's JSONContext.
+	Context []ContextEntry
+	// AnyAllConditions are evaluated per element; an element is skipped when they don't pass.
+	AnyAllConditions AnyAllConditions
+	// PatchStrategy selects how an element's patch is produced. Empty means the element is
+	// handed to mutate.ForEach, which applies no patch.
+	PatchStrategy PatchStrategy
+	// RawPatchStrategicMerge is the strategic merge patch template authored per element for
+	// when PatchStrategy is empty. mutate.ForEach does not apply it; a non-nil value only
+	// reverses the order the elements are processed in.
+	RawPatchStrategicMerge *Target
+	// RawTargetElement is the desired end-state an element is diffed against when
+	// PatchStrategy is TwoWayMerge.
+	RawTargetElement *Target
+	// ForEachMutation holds a nested []ForEachMutation, serialized, when this block itself
+	// iterates over a nested list.
+	ForEachMutation []byte
+	// Parallelism, when set to more than 1, bounds how many elements of this foreach block
+	// (and any nested foreach it spawns) are evaluated concurrently. Nil means sequential.
+	Parallelism *int
+	// ErrorPolicy controls how a failing element is handled. Empty falls back to
+	// ContinueOnError.
+	ErrorPolicy ForEachErrorPolicy
+	// ContinueOnError is the legacy boolean predecessor of ErrorPolicy: true maps to
+	// ErrorPolicySkip, false maps to ErrorPolicyFail.
+	ContinueOnError bool
+	// ConflictPolicy controls how patches from different elements that touch the same JSON
+	// pointer path are reconciled. Empty defaults to ConflictPolicyFailOnConflict.
+	ConflictPolicy ConflictPolicy
+}
@@ -0,0 +1,129 @@
+// Package api holds the engine-facing types a rule handler evaluates against and reports
+// back through: the policy context it reads from, and the rule response it builds.
+package api
+
+import (
+	"context"
+
+	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// RuleStatus is the outcome of evaluating a rule, or one mutate.foreach element/block.
+type RuleStatus string
+
+const (
+	RuleStatusPass  RuleStatus = "pass"
+	RuleStatusFail  RuleStatus = "fail"
+	RuleStatusError RuleStatus = "error"
+	RuleStatusSkip  RuleStatus = "skip"
+)
+
+// RuleType distinguishes which part of a policy a RuleResponse reports on.
+type RuleType string
+
+const (
+	Mutation RuleType = "Mutation"
+)
+
+// JSONContext is the per-request evaluation scope a rule is checked against: JMESPath
+// queries, loaded context entries, and the resource under evaluation all flow through it.
+type JSONContext interface {
+	// Checkpoint saves the current context state so it can be Restore()d later, letting a
+	// foreach block try an element without leaking its context mutations to the next one.
+	Checkpoint()
+	// Restore reverts to the most recent Checkpoint.
+	Restore()
+	// Reset clears any per-element bindings (e.g. "element"/"elementIndex") added since the
+	// last Checkpoint, without discarding the checkpoint itself.
+	Reset()
+	// AddResource replaces the resource object visible to subsequent JMESPath queries.
+	AddResource(resource map[string]interface{}) error
+	// Query evaluates a JMESPath expression against the current context.
+	Query(query string) (interface{}, error)
+}
+
+// EngineContextLoader loads a rule or foreach block's declared context entries into ctx.
+type EngineContextLoader func(ctx context.Context, entries []kyvernov1.ContextEntry, jsonContext JSONContext) error
+
+// PolicyContext carries the state of one admission request as it is evaluated against a
+// policy.
+type PolicyContext interface {
+	// JSONContext returns the JMESPath evaluation scope for this request.
+	JSONContext() JSONContext
+	// Copy returns an independent PolicyContext seeded from this one's current state, so a
+	// foreach element's context mutations don't leak into sibling elements.
+	Copy() PolicyContext
+	// ExplainMutation reports whether the engine is running in dry-run/explain mode, where
+	// patches are computed and their provenance reported but never applied to live state.
+	ExplainMutation() bool
+}
+
+// ElementError records a mutate.foreach element that failed under ErrorPolicy Collect.
+type ElementError struct {
+	Index   int
+	Path    string
+	Message string
+}
+
+// NewElementError builds an ElementError for the element at index, located at path.
+func NewElementError(index int, path, message string) ElementError {
+	return ElementError{Index: index, Path: path, Message: message}
+}
+
+// PatchOrigin records, in explain mode, which mutate.foreach element and nesting level a
+// single patch operation was produced by.
+type PatchOrigin struct {
+	Path         string
+	NestingLevel int
+	ElementIndex int
+	Element      interface{}
+	Source       string
+}
+
+// RuleResponse is the externally-visible outcome of evaluating one rule.
+type RuleResponse struct {
+	Name            string
+	Type            RuleType
+	Message         string
+	Status          RuleStatus
+	Patches         [][]byte
+	PatchedTarget   *unstructured.Unstructured
+	ElementErrors   []ElementError
+	PatchProvenance []PatchOrigin
+}
+
+// NewRuleResponse builds the base RuleResponse for a rule that finished with status.
+func NewRuleResponse(name string, ruleType RuleType, message string, status RuleStatus) *RuleResponse {
+	return &RuleResponse{Name: name, Type: ruleType, Message: message, Status: status}
+}
+
+// WithPatches attaches the JSON patch operations the rule produced against the triggering
+// resource.
+func (r *RuleResponse) WithPatches(patches ...[]byte) *RuleResponse {
+	r.Patches = append(r.Patches, patches...)
+	return r
+}
+
+// WithPatchedTarget attaches the patched form of an additional target resource the rule
+// mutated (rule.Mutation.Targets). gvr and subresource are accepted but not recorded on the
+// response.
+func (r *RuleResponse) WithPatchedTarget(target *unstructured.Unstructured, gvr schema.GroupVersionResource, subresource string) *RuleResponse {
+	r.PatchedTarget = target
+	return r
+}
+
+// WithElementErrors attaches the mutate.foreach elements that failed under ErrorPolicy
+// Collect.
+func (r *RuleResponse) WithElementErrors(errs ...ElementError) *RuleResponse {
+	r.ElementErrors = append(r.ElementErrors, errs...)
+	return r
+}
+
+// WithPatchProvenance attaches the per-element patch provenance computed in explain mode, so
+// a CLI consumer can render which element/nesting level produced each patch operation.
+func (r *RuleResponse) WithPatchProvenance(origins ...PatchOrigin) *RuleResponse {
+	r.PatchProvenance = append(r.PatchProvenance, origins...)
+	return r
+}
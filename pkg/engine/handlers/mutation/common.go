@@ -4,7 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"reflect"
+	"strings"
+	"sync"
 
+	jsonpatchv5 "github.com/evanphx/json-patch"
 	"github.com/go-logr/logr"
 	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
 	engineapi "github.com/kyverno/kyverno/pkg/engine/api"
@@ -12,6 +16,7 @@ import (
 	"github.com/kyverno/kyverno/pkg/engine/mutate"
 	"github.com/kyverno/kyverno/pkg/engine/mutate/patch"
 	engineutils "github.com/kyverno/kyverno/pkg/engine/utils"
+	"github.com/kyverno/kyverno/pkg/engine/variables"
 	"github.com/kyverno/kyverno/pkg/utils/api"
 	"github.com/mattbaird/jsonpatch"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -25,11 +30,29 @@ type forEachMutator struct {
 	resource      resourceInfo
 	nesting       int
 	contextLoader engineapi.EngineContextLoader
+	// sem bounds the number of elements evaluated concurrently across this forEachMutator
+	// and any nested foreach it spawns, so a nested foreach inherits the parent's
+	// parallelism budget instead of multiplying it. Nil means no budget is inherited: each
+	// foreach block then runs sequentially unless its own Parallelism opts in (see
+	// resolveSemaphore).
+	sem chan struct{}
+}
+
+// indexedPatch tags a raw JSON patch operation with the foreach element index that
+// produced it, so conflicting operations on the same path can be reported against their
+// originating elements.
+type indexedPatch struct {
+	elementIndex int
+	data         []byte
 }
 
 func (f *forEachMutator) mutateForEach(ctx context.Context) *mutate.Response {
+	explain := f.policyContext.ExplainMutation()
+
 	var applyCount int
 	allPatches := make([][]byte, 0)
+	var provenance []engineapi.PatchOrigin
+	var elementErrors []engineapi.ElementError
 
 	for _, foreach := range f.foreach {
 		elements, err := engineutils.EvaluateList(foreach.List, f.policyContext.JSONContext())
@@ -49,31 +72,147 @@ func (f *forEachMutator) mutateForEach(ctx context.Context) *mutate.Response {
 				f.resource.unstructured = mutateResp.PatchedResource
 				allPatches = append(allPatches, mutateResp.Patches...)
 			}
+			if mutateResp.Status == engineapi.RuleStatusFail {
+				return mutateResp
+			}
+			elementErrors = append(elementErrors, mutateResp.ElementErrors...)
+			provenance = append(provenance, mutateResp.PatchProvenance...)
 			f.logger.Info("mutateResp.PatchedResource", "resource", mutateResp.PatchedResource)
-			if err := f.policyContext.JSONContext().AddResource(mutateResp.PatchedResource.Object); err != nil {
-				f.logger.Error(err, "failed to update resource in context")
+			// In explain mode the patched resource is never pushed into the shared
+			// JSONContext, so downstream rules keep observing the pristine resource.
+			if !explain {
+				if err := f.policyContext.JSONContext().AddResource(mutateResp.PatchedResource.Object); err != nil {
+					f.logger.Error(err, "failed to update resource in context")
+				}
 			}
 		}
 	}
 
 	msg := fmt.Sprintf("%d elements processed", applyCount)
+	status := engineapi.RuleStatusPass
 	if applyCount == 0 {
-		return mutate.NewResponse(engineapi.RuleStatusSkip, f.resource.unstructured, allPatches, msg)
+		status = engineapi.RuleStatusSkip
 	}
-
-	return mutate.NewResponse(engineapi.RuleStatusPass, f.resource.unstructured, allPatches, msg)
+	resp := mutate.NewResponse(status, f.resource.unstructured, allPatches, msg)
+	if len(elementErrors) > 0 {
+		resp = resp.WithElementErrors(elementErrors...)
+	}
+	if len(provenance) > 0 {
+		resp = resp.WithPatchProvenance(provenance...)
+	}
+	return resp
 }
 
 func (f *forEachMutator) mutateElements(ctx context.Context, foreach kyvernov1.ForEachMutation, elements []interface{}) *mutate.Response {
 	f.policyContext.JSONContext().Checkpoint()
 	defer f.policyContext.JSONContext().Restore()
 
-	patchedResource := f.resource
-	var allPatches [][]byte
 	if foreach.RawPatchStrategicMerge != nil {
 		engineutils.InvertedElement(elements)
 	}
 
+	sem := resolveSemaphore(f.sem, foreach.Parallelism)
+
+	var allPatches []indexedPatch
+	var patchedResource resourceInfo
+	var elementErrors []engineapi.ElementError
+	var provenance []engineapi.PatchOrigin
+	if sem != nil {
+		var resp *mutate.Response
+		allPatches, patchedResource, elementErrors, provenance, resp = f.mutateElementsParallel(ctx, foreach, elements, sem)
+		if resp != nil {
+			return resp
+		}
+	} else {
+		var resp *mutate.Response
+		allPatches, patchedResource, elementErrors, provenance, resp = f.mutateElementsSequential(ctx, foreach, elements, sem)
+		if resp != nil {
+			return resp
+		}
+	}
+
+	resolvedOps, err := resolvePatchConflicts(foreach, allPatches)
+	if err != nil {
+		return mutate.NewErrorResponse("conflicting mutate.foreach patches", err)
+	}
+	sortedPatches := patch.FilterAndSortPatches(resolvedOps)
+	var finalPatches [][]byte
+	for _, p := range sortedPatches {
+		if data, err := p.MarshalJSON(); err != nil {
+			return mutate.NewErrorResponse("failed to marshal patch", err)
+		} else {
+			finalPatches = append(finalPatches, data)
+		}
+	}
+
+	// Rebuild the merged object by replaying the final, conflict-resolved patch set against
+	// the original base resource, rather than trusting whichever worker's patchedResource was
+	// recorded last - under Parallelism that object reflects only one element's mutation, not
+	// the union of all of them.
+	mergedResource := patchedResource.unstructured
+	if len(finalPatches) > 0 {
+		mergedResource, err = engineutils.ApplyPatches(f.resource.unstructured, finalPatches)
+		if err != nil {
+			return mutate.NewErrorResponse("failed to apply merged mutate.foreach patches", err)
+		}
+	}
+
+	message := ""
+	if len(elementErrors) > 0 {
+		message = fmt.Sprintf("%d element(s) failed and were skipped", len(elementErrors))
+	}
+	resp := mutate.NewResponse(engineapi.RuleStatusPass, mergedResource, finalPatches, message)
+	if len(elementErrors) > 0 {
+		resp = resp.WithElementErrors(elementErrors...)
+	}
+	if len(provenance) > 0 {
+		resp = resp.WithPatchProvenance(provenance...)
+	}
+	return resp
+}
+
+// resolveSemaphore determines the concurrency limiter to use for one foreach block. A
+// nested forEachMutator always reuses the ancestor-provided budget (inherited, not
+// multiplied per nesting level) regardless of its own Parallelism value; a top-level foreach
+// block only gets a semaphore when it explicitly opts in via Parallelism, and that budget is
+// never cached beyond this one call, so a sibling foreach block that doesn't opt in stays
+// sequential even if an earlier sibling requested Parallelism.
+func resolveSemaphore(inherited chan struct{}, parallelism *int) chan struct{} {
+	if inherited != nil {
+		return inherited
+	}
+	if parallelism != nil && *parallelism > 1 {
+		return make(chan struct{}, *parallelism)
+	}
+	return nil
+}
+
+// elementErrorPolicy returns the effective error handling policy for a foreach block,
+// falling back to the legacy ContinueOnError boolean when ErrorPolicy is not set.
+func elementErrorPolicy(foreach kyvernov1.ForEachMutation) kyvernov1.ForEachErrorPolicy {
+	if foreach.ErrorPolicy != "" {
+		return foreach.ErrorPolicy
+	}
+	if foreach.ContinueOnError {
+		return kyvernov1.ErrorPolicySkip
+	}
+	return kyvernov1.ErrorPolicyFail
+}
+
+// mutateElementsSequential processes elements one at a time in order, exactly as before
+// parallelism was introduced. It returns the accumulated patches, the resulting patched
+// resource, and a non-nil response when the block must end with that response instead: the
+// first failing element under ErrorPolicy Fail, or a Fail response when every element that
+// ran failed.
+func (f *forEachMutator) mutateElementsSequential(ctx context.Context, foreach kyvernov1.ForEachMutation, elements []interface{}, sem chan struct{}) ([]indexedPatch, resourceInfo, []engineapi.ElementError, []engineapi.PatchOrigin, *mutate.Response) {
+	explain := f.policyContext.ExplainMutation()
+	patchedResource := f.resource
+	var allPatches []indexedPatch
+	var elementErrors []engineapi.ElementError
+	var provenance []engineapi.PatchOrigin
+	var successCount, failCount int
+	policy := elementErrorPolicy(foreach)
+
 	for index, element := range elements {
 		if element == nil {
 			continue
@@ -82,80 +221,590 @@ func (f *forEachMutator) mutateElements(ctx context.Context, foreach kyvernov1.F
 		f.policyContext.JSONContext().Reset()
 		policyContext := f.policyContext.Copy()
 
-		falseVar := false
-		if err := engineutils.AddElementToContext(policyContext, element, index, f.nesting, &falseVar); err != nil {
-			return mutate.NewErrorResponse(fmt.Sprintf("failed to add element to mutate.foreach[%d].context", index), err)
+		mutateResp, skip, err := f.processElement(ctx, foreach, policyContext, patchedResource, element, index, sem)
+		if err != nil {
+			if policy == kyvernov1.ErrorPolicyFail {
+				return nil, patchedResource, nil, nil, mutate.NewErrorResponse(fmt.Sprintf("failed to mutate mutate.foreach[%d]", index), err)
+			}
+			f.logger.Error(err, "mutate.foreach element failed, continuing", "elementIndex", index)
+			failCount++
+			if policy == kyvernov1.ErrorPolicyCollect {
+				elementErrors = append(elementErrors, engineapi.NewElementError(index, elementJSONPointer(foreach.List, index), err.Error()))
+			}
+			continue
+		}
+		if skip {
+			continue
+		}
+
+		if mutateResp.Status == engineapi.RuleStatusFail || mutateResp.Status == engineapi.RuleStatusError {
+			if policy == kyvernov1.ErrorPolicyFail {
+				return nil, patchedResource, nil, nil, mutateResp
+			}
+			f.logger.Info("mutate.foreach element did not apply, continuing", "elementIndex", index, "message", mutateResp.Message)
+			failCount++
+			if policy == kyvernov1.ErrorPolicyCollect {
+				elementErrors = append(elementErrors, engineapi.NewElementError(index, elementJSONPointer(foreach.List, index), mutateResp.Message))
+			}
+			continue
+		}
+
+		successCount++
+		if len(mutateResp.Patches) > 0 {
+			patchedResource.unstructured = mutateResp.PatchedResource
+			for _, p := range mutateResp.Patches {
+				allPatches = append(allPatches, indexedPatch{elementIndex: index, data: p})
+			}
+			if explain {
+				provenance = append(provenance, elementProvenance(f.nesting, index, element, foreach, mutateResp)...)
+			}
 		}
+	}
 
-		if err := f.contextLoader(ctx, foreach.Context, policyContext.JSONContext()); err != nil {
-			return mutate.NewErrorResponse(fmt.Sprintf("failed to load to mutate.foreach[%d].context", index), err)
+	// failCount, not len(elementErrors), decides this: under ErrorPolicy Skip (including the
+	// legacy ContinueOnError=true mapping) failed elements are intentionally never recorded
+	// as ElementErrors, but the rule must still report Fail rather than silently Pass when
+	// every element failed.
+	if failCount > 0 && successCount == 0 {
+		msg := fmt.Sprintf("all %d mutate.foreach elements failed", failCount)
+		resp := mutate.NewResponse(engineapi.RuleStatusFail, patchedResource.unstructured, nil, msg)
+		if len(elementErrors) > 0 {
+			resp = resp.WithElementErrors(elementErrors...)
 		}
+		return nil, patchedResource, elementErrors, nil, resp
+	}
 
-		preconditionsPassed, err := internal.CheckPreconditions(f.logger, policyContext.JSONContext(), foreach.AnyAllConditions)
-		if err != nil {
-			return mutate.NewErrorResponse(fmt.Sprintf("failed to evaluate mutate.foreach[%d].preconditions", index), err)
+	return allPatches, patchedResource, elementErrors, provenance, nil
+}
+
+// elementProvenance records where a patch op came from: the nesting level and element index
+// it was produced at, the element value itself, and the foreach fragment that generated it.
+// A nested foreach's mutateResp already carries its own deeper PatchProvenance entries, which
+// are reused as-is: each op is attributed to the innermost nesting level and element that
+// produced it, and this level's element is not recorded for it.
+func elementProvenance(nesting, index int, element interface{}, foreach kyvernov1.ForEachMutation, mutateResp *mutate.Response) []engineapi.PatchOrigin {
+	if len(mutateResp.PatchProvenance) > 0 {
+		return mutateResp.PatchProvenance
+	}
+	origins := make([]engineapi.PatchOrigin, 0, len(mutateResp.Patches))
+	for _, p := range mutateResp.Patches {
+		var op jsonpatch.JsonPatchOperation
+		if err := json.Unmarshal(p, &op); err != nil {
+			continue
 		}
+		origins = append(origins, engineapi.PatchOrigin{
+			Path:         op.Path,
+			NestingLevel: nesting,
+			ElementIndex: index,
+			Element:      element,
+			Source:       foreach.List,
+		})
+	}
+	return origins
+}
+
+// mutateElementsParallel fans element evaluation out across a worker pool bounded by sem.
+// Each worker operates against a cloned PolicyContext and the same base patched resource, so
+// it never observes another worker's in-flight patches; results are merged back in index
+// order once every worker has finished, keeping patch ordering deterministic. Under
+// ErrorPolicy Fail, the first element to fail or error cancels the shared context so the
+// remaining workers stop early; under Skip and Collect every element runs.
+func (f *forEachMutator) mutateElementsParallel(ctx context.Context, foreach kyvernov1.ForEachMutation, elements []interface{}, sem chan struct{}) ([]indexedPatch, resourceInfo, []engineapi.ElementError, []engineapi.PatchOrigin, *mutate.Response) {
+	explain := f.policyContext.ExplainMutation()
+	patchedResource := f.resource
+	policy := elementErrorPolicy(foreach)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]*mutate.Response, len(elements))
+	errs := make([]error, len(elements))
 
-		if !preconditionsPassed {
-			f.logger.Info("mutate.foreach.preconditions not met", "elementIndex", index)
+	var wg sync.WaitGroup
+dispatch:
+	for index, element := range elements {
+		if element == nil {
 			continue
 		}
 
-		var mutateResp *mutate.Response
-		if foreach.ForEachMutation != nil {
-			nestedForEach, err := api.DeserializeJSONArray[kyvernov1.ForEachMutation](foreach.ForEachMutation)
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			break dispatch
+		}
+
+		wg.Add(1)
+		go func(index int, element interface{}) {
+			defer wg.Done()
+
+			var released bool
+			release := func() {
+				if !released {
+					released = true
+					<-sem
+				}
+			}
+			defer release()
+
+			defer func() {
+				if r := recover(); r != nil {
+					err := fmt.Errorf("panic processing mutate.foreach[%d]: %v", index, r)
+					f.logger.Error(err, "recovered from panic in mutate.foreach worker", "elementIndex", index)
+					errs[index] = err
+					if policy == kyvernov1.ErrorPolicyFail {
+						cancel()
+					}
+				}
+			}()
+
+			if ctx.Err() != nil {
+				return
+			}
+
+			policyContext := f.policyContext.Copy()
+			policyContext.JSONContext().Reset()
+
+			// A nested foreach shares this worker pool's semaphore rather than its own (so
+			// parallelism budgets don't multiply per nesting level, see resolveSemaphore).
+			// Release this worker's slot before recursing into it: holding the slot while
+			// the nested dispatch loop tries to acquire from the very same channel would
+			// deadlock as soon as the pool is saturated.
+			if foreach.ForEachMutation != nil {
+				release()
+			}
+
+			mutateResp, skip, err := f.processElement(ctx, foreach, policyContext, patchedResource, element, index, sem)
 			if err != nil {
-				return mutate.NewErrorResponse("failed to deserialize foreach", err)
+				errs[index] = err
+				if policy == kyvernov1.ErrorPolicyFail {
+					cancel()
+				}
+				return
+			}
+			if skip {
+				return
 			}
 
-			m := &forEachMutator{
-				rule:          f.rule,
-				policyContext: f.policyContext,
-				resource:      patchedResource,
-				logger:        f.logger,
-				foreach:       nestedForEach,
-				nesting:       f.nesting + 1,
-				contextLoader: f.contextLoader,
+			results[index] = mutateResp
+			if (mutateResp.Status == engineapi.RuleStatusFail || mutateResp.Status == engineapi.RuleStatusError) && policy == kyvernov1.ErrorPolicyFail {
+				cancel()
 			}
+		}(index, element)
+	}
+	wg.Wait()
 
-			mutateResp = m.mutateForEach(ctx)
-		} else {
-			mutateResp = mutate.ForEach(f.rule.Name, foreach, policyContext, patchedResource.unstructured, element, f.logger)
+	if policy == kyvernov1.ErrorPolicyFail {
+		for index, err := range errs {
+			if err != nil {
+				return nil, patchedResource, nil, nil, mutate.NewErrorResponse(fmt.Sprintf("failed to mutate mutate.foreach[%d]", index), err)
+			}
 		}
+	}
 
+	var allPatches []indexedPatch
+	var elementErrors []engineapi.ElementError
+	var provenance []engineapi.PatchOrigin
+	var successCount, failCount int
+	for index, mutateResp := range results {
+		if err := errs[index]; err != nil {
+			f.logger.Error(err, "mutate.foreach element failed, continuing", "elementIndex", index)
+			failCount++
+			if policy == kyvernov1.ErrorPolicyCollect {
+				elementErrors = append(elementErrors, engineapi.NewElementError(index, elementJSONPointer(foreach.List, index), err.Error()))
+			}
+			continue
+		}
+		if mutateResp == nil {
+			continue
+		}
 		if mutateResp.Status == engineapi.RuleStatusFail || mutateResp.Status == engineapi.RuleStatusError {
-			return mutateResp
+			if policy == kyvernov1.ErrorPolicyFail {
+				return nil, patchedResource, nil, nil, mutateResp
+			}
+			f.logger.Info("mutate.foreach element did not apply, continuing", "elementIndex", index, "message", mutateResp.Message)
+			failCount++
+			if policy == kyvernov1.ErrorPolicyCollect {
+				elementErrors = append(elementErrors, engineapi.NewElementError(index, elementJSONPointer(foreach.List, index), mutateResp.Message))
+			}
+			continue
 		}
-
+		successCount++
 		if len(mutateResp.Patches) > 0 {
-			patchedResource.unstructured = mutateResp.PatchedResource
-			allPatches = append(allPatches, mutateResp.Patches...)
+			// patchedResource is deliberately left untouched here: each worker only ever
+			// saw the original base resource (never another worker's in-flight patch), so
+			// recording any single worker's PatchedResource would discard every other
+			// element's mutation. The caller rebuilds the real merged object from the
+			// union of allPatches once conflicts are resolved.
+			for _, p := range mutateResp.Patches {
+				allPatches = append(allPatches, indexedPatch{elementIndex: index, data: p})
+			}
+			if explain {
+				provenance = append(provenance, elementProvenance(f.nesting, index, elements[index], foreach, mutateResp)...)
+			}
 		}
 	}
-	var sortedPatches []jsonpatch.JsonPatchOperation
-	for _, p := range allPatches {
-		var jp jsonpatch.JsonPatchOperation
-		if err := json.Unmarshal(p, &jp); err != nil {
-			return mutate.NewErrorResponse("failed to convert patch", err)
+
+	// failCount, not len(elementErrors), decides this: under ErrorPolicy Skip failed elements
+	// are intentionally never recorded as ElementErrors, but the rule must still report Fail
+	// rather than silently Pass when every element failed.
+	if failCount > 0 && successCount == 0 {
+		msg := fmt.Sprintf("all %d mutate.foreach elements failed", failCount)
+		resp := mutate.NewResponse(engineapi.RuleStatusFail, patchedResource.unstructured, nil, msg)
+		if len(elementErrors) > 0 {
+			resp = resp.WithElementErrors(elementErrors...)
 		}
-		sortedPatches = append(sortedPatches, jp)
+		return nil, patchedResource, elementErrors, nil, resp
 	}
-	sortedPatches = patch.FilterAndSortPatches(sortedPatches)
-	var finalPatches [][]byte
-	for _, p := range sortedPatches {
-		if data, err := p.MarshalJSON(); err != nil {
-			return mutate.NewErrorResponse("failed to marshal patch", err)
+
+	return allPatches, patchedResource, elementErrors, provenance, nil
+}
+
+// processElement evaluates the foreach context and preconditions for a single element and,
+// if preconditions pass, produces its mutate.Response. skip is true when the element's
+// preconditions were not met and it should be silently excluded from the result.
+func (f *forEachMutator) processElement(ctx context.Context, foreach kyvernov1.ForEachMutation, policyContext engineapi.PolicyContext, patchedResource resourceInfo, element interface{}, index int, sem chan struct{}) (resp *mutate.Response, skip bool, err error) {
+	falseVar := false
+	if err := engineutils.AddElementToContext(policyContext, element, index, f.nesting, &falseVar); err != nil {
+		return nil, false, fmt.Errorf("failed to add element to mutate.foreach[%d].context: %v", index, err)
+	}
+
+	if err := f.contextLoader(ctx, foreach.Context, policyContext.JSONContext()); err != nil {
+		return nil, false, fmt.Errorf("failed to load to mutate.foreach[%d].context: %v", index, err)
+	}
+
+	preconditionsPassed, err := internal.CheckPreconditions(f.logger, policyContext.JSONContext(), foreach.AnyAllConditions)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to evaluate mutate.foreach[%d].preconditions: %v", index, err)
+	}
+
+	if !preconditionsPassed {
+		f.logger.Info("mutate.foreach.preconditions not met", "elementIndex", index)
+		return nil, true, nil
+	}
+
+	if foreach.PatchStrategy == kyvernov1.TwoWayMerge {
+		resp, err = f.mutateTwoWayMerge(foreach, policyContext, patchedResource.unstructured, element, index)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to compute two-way merge patch for mutate.foreach[%d]: %v", index, err)
+		}
+		return resp, false, nil
+	}
+
+	if foreach.ForEachMutation != nil {
+		nestedForEach, err := api.DeserializeJSONArray[kyvernov1.ForEachMutation](foreach.ForEachMutation)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to deserialize foreach: %v", err)
+		}
+
+		m := &forEachMutator{
+			rule:          f.rule,
+			policyContext: f.policyContext,
+			resource:      patchedResource,
+			logger:        f.logger,
+			foreach:       nestedForEach,
+			nesting:       f.nesting + 1,
+			contextLoader: f.contextLoader,
+			// Inherit the budget active for *this* foreach call, not f.sem (which reflects
+			// only the ancestor this forEachMutator itself was constructed with) - so the
+			// nested foreach shares the same concurrency ceiling its parent call is
+			// currently bounded by, instead of multiplying it.
+			sem: sem,
+		}
+
+		return m.mutateForEach(ctx), false, nil
+	}
+
+	return mutate.ForEach(f.rule.Name, foreach, policyContext, patchedResource.unstructured, element, f.logger), false, nil
+}
+
+// mutateTwoWayMerge evaluates foreach.RawTargetElement against the current element's context
+// to produce the desired end-state of the element, diffs it against the original element with
+// a plain JSON merge patch (RFC 7396), and rewrites the resulting merge patch into JSON patch
+// operations rooted at the element's position under foreach.List.
+//
+// This is a generic, type-agnostic diff, not a schema-aware strategic merge: there is no
+// resource type information available here to resolve list merge keys, so array fields are
+// always replaced wholesale rather than merged element-by-element. Callers that need
+// merge-key-aware list handling should author the patch with RawPatchStrategicMerge instead,
+// where the real resource schema is available.
+func (f *forEachMutator) mutateTwoWayMerge(foreach kyvernov1.ForEachMutation, policyContext engineapi.PolicyContext, resource unstructured.Unstructured, element interface{}, index int) (*mutate.Response, error) {
+	if foreach.RawTargetElement == nil {
+		return nil, fmt.Errorf("mutate.foreach.targetElement is required when patchStrategy is %s", kyvernov1.TwoWayMerge)
+	}
+
+	// A filter expression such as "[?name=='nginx']" reorders/subsets the list, so index is
+	// the element's position in the filtered result, not its real position in the underlying
+	// array. elementJSONPointer has no way to recover the real index from a filter
+	// expression, so reject it here rather than silently patching the wrong array slot.
+	if strings.Contains(foreach.List, "[?") {
+		return nil, fmt.Errorf("mutate.foreach.patchStrategy %s does not support a filtered mutate.foreach.list expression (%q): the loop index is the position in the filtered result, not the real array index", kyvernov1.TwoWayMerge, foreach.List)
+	}
+
+	targetRaw, err := variables.SubstituteAll(f.logger, policyContext.JSONContext(), foreach.RawTargetElement.Object)
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate targetElement: %v", err)
+	}
+
+	originalJSON, err := json.Marshal(element)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal original element: %v", err)
+	}
+
+	targetJSON, err := json.Marshal(targetRaw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal target element: %v", err)
+	}
+
+	mergePatch, err := jsonpatchv5.CreateMergePatch(originalJSON, targetJSON)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create merge patch: %v", err)
+	}
+
+	basePath := elementJSONPointer(foreach.List, index)
+	patches, err := mergePatchToJSONPatch(basePath, originalJSON, mergePatch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert merge patch to JSON patch: %v", err)
+	}
+
+	if len(patches) == 0 {
+		return mutate.NewResponse(engineapi.RuleStatusSkip, resource, nil, "no changes from two-way merge"), nil
+	}
+
+	patchedResource, err := engineutils.ApplyPatches(resource, patches)
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply two-way merge patch: %v", err)
+	}
+
+	return mutate.NewResponse(engineapi.RuleStatusPass, patchedResource, patches, "mutated via two-way merge"), nil
+}
+
+// resourceContextRoots lists the JMESPath roots foreach.List is commonly evaluated against
+// that wrap the actual resource (the admission request payload, not the object being
+// patched). They must be stripped before the remainder of the path can be used as a JSON
+// pointer into the resource itself.
+var resourceContextRoots = []string{"request.object", "request.oldObject"}
+
+// stripResourceContextRoot removes a leading context root such as "request.object" from
+// listPath, so "request.object.spec.containers" becomes "spec.containers" - the shape the
+// resource actually has, rather than the admission review wrapper the expression is
+// evaluated against.
+func stripResourceContextRoot(listPath string) string {
+	for _, root := range resourceContextRoots {
+		if listPath == root {
+			return ""
+		}
+		if rest, ok := strings.CutPrefix(listPath, root+"."); ok {
+			return rest
+		}
+	}
+	return listPath
+}
+
+// elementJSONPointer builds the JSON pointer locating the element at index within the
+// resource, from the JMESPath expression used to evaluate foreach.List.
+func elementJSONPointer(listPath string, index int) string {
+	p := stripResourceContextRoot(listPath)
+	p = strings.ReplaceAll(p, "[", ".")
+	p = strings.ReplaceAll(p, "]", "")
+	segments := strings.Split(p, ".")
+	pointer := ""
+	for _, s := range segments {
+		if s == "" {
+			continue
+		}
+		pointer += "/" + s
+	}
+	return fmt.Sprintf("%s/%d", pointer, index)
+}
+
+// mergePatchToJSONPatch rewrites a JSON merge patch (RFC 7396, as produced by
+// jsonpatchv5.CreateMergePatch) into a set of JSON patch operations rooted at basePath.
+//
+// A merge patch fragment for a nested object only lists the sub-keys that changed, not the
+// object's full contents, so it recurses into nested objects that existed in original and
+// emits an op per changed leaf rather than one "replace" of the whole containing object -
+// otherwise any untouched sibling field of a multi-field nested object (e.g.
+// resources.limits.memory when only resources.limits.cpu changed) would be silently dropped.
+// A field that didn't previously exist is added in a single op instead, since there are no
+// sibling fields under it to lose.
+func mergePatchToJSONPatch(basePath string, originalJSON []byte, mergePatch []byte) ([][]byte, error) {
+	var changes map[string]interface{}
+	if err := json.Unmarshal(mergePatch, &changes); err != nil {
+		return nil, err
+	}
+	var original map[string]interface{}
+	if err := json.Unmarshal(originalJSON, &original); err != nil {
+		return nil, err
+	}
+	return mergePatchFieldsToJSONPatch(basePath, original, changes)
+}
+
+func mergePatchFieldsToJSONPatch(basePath string, original, changes map[string]interface{}) ([][]byte, error) {
+	var patches [][]byte
+	for field, value := range changes {
+		path := basePath + "/" + field
+
+		if nested, ok := value.(map[string]interface{}); ok {
+			if existing, ok := original[field].(map[string]interface{}); ok {
+				nestedPatches, err := mergePatchFieldsToJSONPatch(path, existing, nested)
+				if err != nil {
+					return nil, err
+				}
+				patches = append(patches, nestedPatches...)
+				continue
+			}
+		}
+
+		op := jsonpatch.JsonPatchOperation{Path: path}
+		if value == nil {
+			op.Operation = "remove"
 		} else {
-			finalPatches = append(finalPatches, data)
+			// add, not replace: the field may not have existed in original at all (e.g. a
+			// wholly new nested object), and add covers both that case and replacing an
+			// existing field's value.
+			op.Operation = "add"
+			op.Value = value
+		}
+		data, err := op.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		patches = append(patches, data)
+	}
+	return patches, nil
+}
+
+// pathOp tags a parsed JSON patch operation with the foreach element index that produced
+// it, for reporting conflicts against their originating elements.
+type pathOp struct {
+	elementIndex int
+	op           jsonpatch.JsonPatchOperation
+}
+
+// resolvePatchConflicts groups the patches produced by every foreach element by JSON
+// pointer path and, for paths touched by more than one element, decides what to do about
+// it according to foreach.ConflictPolicy. Paths with a single contributing op, or with
+// multiple ops that happen to agree, pass through unchanged.
+func resolvePatchConflicts(foreach kyvernov1.ForEachMutation, patches []indexedPatch) ([]jsonpatch.JsonPatchOperation, error) {
+	policy := foreach.ConflictPolicy
+	if policy == "" {
+		policy = kyvernov1.ConflictPolicyFailOnConflict
+	}
+
+	var order []string
+	byPath := map[string][]pathOp{}
+	for _, p := range patches {
+		var op jsonpatch.JsonPatchOperation
+		if err := json.Unmarshal(p.data, &op); err != nil {
+			return nil, fmt.Errorf("failed to parse patch from mutate.foreach[%d]: %v", p.elementIndex, err)
+		}
+		if _, ok := byPath[op.Path]; !ok {
+			order = append(order, op.Path)
+		}
+		byPath[op.Path] = append(byPath[op.Path], pathOp{elementIndex: p.elementIndex, op: op})
+	}
+
+	var resolved []jsonpatch.JsonPatchOperation
+	for _, path := range order {
+		ops := byPath[path]
+		if len(ops) == 1 || !opsConflict(ops) {
+			resolved = append(resolved, ops[len(ops)-1].op)
+			continue
+		}
+
+		switch policy {
+		case kyvernov1.ConflictPolicyLastWriterWins:
+			resolved = append(resolved, ops[len(ops)-1].op)
+		case kyvernov1.ConflictPolicyFirstWriterWins:
+			resolved = append(resolved, ops[0].op)
+		case kyvernov1.ConflictPolicyMerge:
+			merged, err := mergeConflictingOps(ops)
+			if err != nil {
+				return nil, err
+			}
+			resolved = append(resolved, merged)
+		default:
+			indices := make([]int, 0, len(ops))
+			values := make([]interface{}, 0, len(ops))
+			for _, o := range ops {
+				indices = append(indices, o.elementIndex)
+				values = append(values, o.op.Value)
+			}
+			return nil, fmt.Errorf("conflicting mutate.foreach patches at %s from elements %v: %v", path, indices, values)
+		}
+	}
+
+	return resolved, nil
+}
+
+// opsConflict reports whether the patch operations targeting the same JSON pointer path
+// disagree, e.g. two replace ops with different values, or a remove followed by a replace.
+func opsConflict(ops []pathOp) bool {
+	first := ops[0].op
+	for _, o := range ops[1:] {
+		if o.op.Operation != first.Operation {
+			return true
+		}
+		if !reflect.DeepEqual(o.op.Value, first.Value) {
+			return true
 		}
 	}
-	return mutate.NewResponse(engineapi.RuleStatusPass, patchedResource.unstructured, finalPatches, "")
+	return false
+}
+
+// mergeConflictingOps resolves same-path conflicts by recursively overriding the contending
+// add/replace operations' values field-by-field, with later elements' fields taking
+// precedence. This is a plain object merge, not a resource-schema-aware strategic merge:
+// there is no type information available here to resolve list merge keys, so an array field
+// set by more than one element is not merged, it is simply overridden by the later element's
+// value like any other non-object value. It cannot merge a remove against a replace/add.
+func mergeConflictingOps(ops []pathOp) (jsonpatch.JsonPatchOperation, error) {
+	merged := ops[0].op.Value
+	for _, o := range ops[1:] {
+		if o.op.Operation == "remove" || ops[0].op.Operation == "remove" {
+			remover, setter := ops[0].elementIndex, o.elementIndex
+			if o.op.Operation == "remove" {
+				remover, setter = o.elementIndex, ops[0].elementIndex
+			}
+			return jsonpatch.JsonPatchOperation{}, fmt.Errorf(
+				"cannot merge conflicting patches at %s: element %d removed the field while element %d set it",
+				o.op.Path, remover, setter)
+		}
+		merged = mergeValue(merged, o.op.Value)
+	}
+	return jsonpatch.JsonPatchOperation{Operation: "replace", Path: ops[0].op.Path, Value: merged}, nil
+}
+
+// mergeValue merges two JSON values field-by-field when both are objects, recursing into
+// fields that are objects on both sides; any other value (including arrays, which are never
+// merged by a list merge key) is simply overridden by b.
+func mergeValue(a, b interface{}) interface{} {
+	am, aok := a.(map[string]interface{})
+	bm, bok := b.(map[string]interface{})
+	if !aok || !bok {
+		return b
+	}
+	merged := make(map[string]interface{}, len(am)+len(bm))
+	for k, v := range am {
+		merged[k] = v
+	}
+	for k, v := range bm {
+		if existing, ok := merged[k]; ok {
+			merged[k] = mergeValue(existing, v)
+		} else {
+			merged[k] = v
+		}
+	}
+	return merged
 }
 
 func buildRuleResponse(rule *kyvernov1.Rule, mutateResp *mutate.Response, info resourceInfo) *engineapi.RuleResponse {
 	message := mutateResp.Message
 	if mutateResp.Status == engineapi.RuleStatusPass {
 		message = buildSuccessMessage(mutateResp.PatchedResource)
+		if len(mutateResp.ElementErrors) > 0 {
+			message = fmt.Sprintf("%s (%d element(s) skipped due to errors)", message, len(mutateResp.ElementErrors))
+		}
 	}
 	resp := engineapi.NewRuleResponse(
 		rule.Name,
@@ -163,12 +812,18 @@ func buildRuleResponse(rule *kyvernov1.Rule, mutateResp *mutate.Response, info r
 		message,
 		mutateResp.Status,
 	)
+	if len(mutateResp.ElementErrors) > 0 {
+		resp = resp.WithElementErrors(mutateResp.ElementErrors...)
+	}
 	if mutateResp.Status == engineapi.RuleStatusPass {
 		resp = resp.WithPatches(mutateResp.Patches...)
 		if len(rule.Mutation.Targets) != 0 {
 			resp = resp.WithPatchedTarget(&mutateResp.PatchedResource, info.parentResourceGVR, info.subresource)
 		}
 	}
+	if len(mutateResp.PatchProvenance) > 0 {
+		resp = resp.WithPatchProvenance(mutateResp.PatchProvenance...)
+	}
 	return resp
 }
 
@@ -0,0 +1,419 @@
+package mutation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	jsonpatchv5 "github.com/evanphx/json-patch"
+	"github.com/go-logr/logr/testr"
+	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
+	engineapi "github.com/kyverno/kyverno/pkg/engine/api"
+	"github.com/kyverno/kyverno/pkg/engine/mutate"
+	engineutils "github.com/kyverno/kyverno/pkg/engine/utils"
+	"github.com/mattbaird/jsonpatch"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestElementJSONPointer(t *testing.T) {
+	tests := []struct {
+		name     string
+		listPath string
+		index    int
+		want     string
+	}{
+		{
+			name:     "strips request.object root",
+			listPath: "request.object.spec.containers",
+			index:    2,
+			want:     "/spec/containers/2",
+		},
+		{
+			name:     "strips request.oldObject root",
+			listPath: "request.oldObject.spec.containers",
+			index:    0,
+			want:     "/spec/containers/0",
+		},
+		{
+			name:     "trailing [] projection is dropped",
+			listPath: "request.object.spec.volumes[]",
+			index:    1,
+			want:     "/spec/volumes/1",
+		},
+		{
+			name:     "already resource-rooted path is left alone",
+			listPath: "spec.containers",
+			index:    3,
+			want:     "/spec/containers/3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := elementJSONPointer(tt.listPath, tt.index)
+			if got != tt.want {
+				t.Errorf("elementJSONPointer(%q, %d) = %q, want %q", tt.listPath, tt.index, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestMutateTwoWayMergeRoundTrip exercises the same conversion mutateTwoWayMerge performs -
+// jsonpatchv5.CreateMergePatch followed by mergePatchToJSONPatch rooted via elementJSONPointer
+// - against a realistic containers[] element whose nested securityContext has more than one
+// field. Only one of those fields changes, which is the regression case for clobbering
+// untouched siblings: applying the resulting ops must change runAsNonRoot but leave
+// readOnlyRootFilesystem exactly as it was, and the op path must not retain the
+// request.object context root.
+func TestMutateTwoWayMergeRoundTrip(t *testing.T) {
+	original := map[string]interface{}{
+		"name":  "nginx",
+		"image": "nginx:1.14",
+		"securityContext": map[string]interface{}{
+			"runAsNonRoot":           false,
+			"readOnlyRootFilesystem": true,
+		},
+	}
+	target := map[string]interface{}{
+		"name":  "nginx",
+		"image": "nginx:1.14",
+		"securityContext": map[string]interface{}{
+			"runAsNonRoot":           true,
+			"readOnlyRootFilesystem": true,
+		},
+	}
+
+	originalJSON, err := json.Marshal(original)
+	if err != nil {
+		t.Fatalf("marshal original: %v", err)
+	}
+	targetJSON, err := json.Marshal(target)
+	if err != nil {
+		t.Fatalf("marshal target: %v", err)
+	}
+
+	mergePatch, err := jsonpatchv5.CreateMergePatch(originalJSON, targetJSON)
+	if err != nil {
+		t.Fatalf("CreateMergePatch: %v", err)
+	}
+
+	basePath := elementJSONPointer("request.object.spec.containers", 2)
+	patches, err := mergePatchToJSONPatch(basePath, originalJSON, mergePatch)
+	if err != nil {
+		t.Fatalf("mergePatchToJSONPatch: %v", err)
+	}
+	if len(patches) != 1 {
+		t.Fatalf("expected exactly one changed leaf, got %d: %v", len(patches), patches)
+	}
+
+	var op jsonpatch.JsonPatchOperation
+	if err := json.Unmarshal(patches[0], &op); err != nil {
+		t.Fatalf("unmarshal patch op: %v", err)
+	}
+
+	wantPath := "/spec/containers/2/securityContext/runAsNonRoot"
+	if op.Path != wantPath {
+		t.Errorf("op.Path = %q, want %q (must not retain the request.object context root, and must be scoped to the changed leaf, not the whole securityContext object)", op.Path, wantPath)
+	}
+
+	patched, err := engineutils.ApplyPatches(unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"spec": map[string]interface{}{
+			"containers": []interface{}{
+				map[string]interface{}{}, map[string]interface{}{}, original,
+			},
+		},
+	}}, patches)
+	if err != nil {
+		t.Fatalf("ApplyPatches: %v", err)
+	}
+	containers := patched.Object["spec"].(map[string]interface{})["containers"].([]interface{})
+	sc := containers[2].(map[string]interface{})["securityContext"].(map[string]interface{})
+	if sc["runAsNonRoot"] != true {
+		t.Errorf("runAsNonRoot = %v, want true", sc["runAsNonRoot"])
+	}
+	if sc["readOnlyRootFilesystem"] != true {
+		t.Errorf("readOnlyRootFilesystem = %v, want true (untouched sibling field must survive the patch)", sc["readOnlyRootFilesystem"])
+	}
+}
+
+func TestElementErrorPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		foreach kyvernov1.ForEachMutation
+		want    kyvernov1.ForEachErrorPolicy
+	}{
+		{
+			name:    "explicit policy wins",
+			foreach: kyvernov1.ForEachMutation{ErrorPolicy: kyvernov1.ErrorPolicyCollect, ContinueOnError: false},
+			want:    kyvernov1.ErrorPolicyCollect,
+		},
+		{
+			name:    "legacy continueOnError maps to skip",
+			foreach: kyvernov1.ForEachMutation{ContinueOnError: true},
+			want:    kyvernov1.ErrorPolicySkip,
+		},
+		{
+			name:    "default is fail",
+			foreach: kyvernov1.ForEachMutation{},
+			want:    kyvernov1.ErrorPolicyFail,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := elementErrorPolicy(tt.foreach); got != tt.want {
+				t.Errorf("elementErrorPolicy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func patchOp(t *testing.T, elementIndex int, operation, path string, value interface{}) indexedPatch {
+	t.Helper()
+	op := jsonpatch.JsonPatchOperation{Operation: operation, Path: path, Value: value}
+	data, err := op.MarshalJSON()
+	if err != nil {
+		t.Fatalf("marshal op: %v", err)
+	}
+	return indexedPatch{elementIndex: elementIndex, data: data}
+}
+
+func TestResolvePatchConflicts(t *testing.T) {
+	t.Run("non-conflicting ops pass through regardless of policy", func(t *testing.T) {
+		patches := []indexedPatch{
+			patchOp(t, 0, "replace", "/spec/containers/0/image", "nginx:1.14"),
+			patchOp(t, 1, "replace", "/spec/containers/1/image", "nginx:1.15"),
+		}
+		resolved, err := resolvePatchConflicts(kyvernov1.ForEachMutation{}, patches)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(resolved) != 2 {
+			t.Fatalf("expected 2 ops, got %d", len(resolved))
+		}
+	})
+
+	t.Run("failOnConflict is the default", func(t *testing.T) {
+		patches := []indexedPatch{
+			patchOp(t, 0, "replace", "/spec/containers/0/image", "nginx:1.14"),
+			patchOp(t, 1, "replace", "/spec/containers/0/image", "nginx:1.15"),
+		}
+		if _, err := resolvePatchConflicts(kyvernov1.ForEachMutation{}, patches); err == nil {
+			t.Fatal("expected a conflict error, got none")
+		}
+	})
+
+	t.Run("lastWriterWins keeps the later element's op", func(t *testing.T) {
+		patches := []indexedPatch{
+			patchOp(t, 0, "replace", "/spec/containers/0/image", "nginx:1.14"),
+			patchOp(t, 1, "replace", "/spec/containers/0/image", "nginx:1.15"),
+		}
+		resolved, err := resolvePatchConflicts(kyvernov1.ForEachMutation{ConflictPolicy: kyvernov1.ConflictPolicyLastWriterWins}, patches)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if resolved[0].Value != "nginx:1.15" {
+			t.Errorf("resolved value = %v, want nginx:1.15", resolved[0].Value)
+		}
+	})
+
+	t.Run("firstWriterWins keeps the earlier element's op", func(t *testing.T) {
+		patches := []indexedPatch{
+			patchOp(t, 0, "replace", "/spec/containers/0/image", "nginx:1.14"),
+			patchOp(t, 1, "replace", "/spec/containers/0/image", "nginx:1.15"),
+		}
+		resolved, err := resolvePatchConflicts(kyvernov1.ForEachMutation{ConflictPolicy: kyvernov1.ConflictPolicyFirstWriterWins}, patches)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if resolved[0].Value != "nginx:1.14" {
+			t.Errorf("resolved value = %v, want nginx:1.14", resolved[0].Value)
+		}
+	})
+
+	t.Run("merge merges object values field-by-field", func(t *testing.T) {
+		patches := []indexedPatch{
+			patchOp(t, 0, "replace", "/spec/containers/0/securityContext", map[string]interface{}{"runAsNonRoot": true}),
+			patchOp(t, 1, "replace", "/spec/containers/0/securityContext", map[string]interface{}{"readOnlyRootFilesystem": true}),
+		}
+		resolved, err := resolvePatchConflicts(kyvernov1.ForEachMutation{ConflictPolicy: kyvernov1.ConflictPolicyMerge}, patches)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		merged, ok := resolved[0].Value.(map[string]interface{})
+		if !ok {
+			t.Fatalf("resolved value is not an object: %#v", resolved[0].Value)
+		}
+		if merged["runAsNonRoot"] != true || merged["readOnlyRootFilesystem"] != true {
+			t.Errorf("merged value missing fields from both elements: %#v", merged)
+		}
+	})
+
+	t.Run("merge recurses into nested objects instead of overriding the whole field", func(t *testing.T) {
+		patches := []indexedPatch{
+			patchOp(t, 0, "replace", "/spec/containers/0/resources", map[string]interface{}{
+				"limits": map[string]interface{}{"cpu": "500m", "memory": "256Mi"},
+			}),
+			patchOp(t, 1, "replace", "/spec/containers/0/resources", map[string]interface{}{
+				"limits": map[string]interface{}{"cpu": "750m"},
+			}),
+		}
+		resolved, err := resolvePatchConflicts(kyvernov1.ForEachMutation{ConflictPolicy: kyvernov1.ConflictPolicyMerge}, patches)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		merged, ok := resolved[0].Value.(map[string]interface{})
+		if !ok {
+			t.Fatalf("resolved value is not an object: %#v", resolved[0].Value)
+		}
+		limits, ok := merged["limits"].(map[string]interface{})
+		if !ok {
+			t.Fatalf("resolved limits is not an object: %#v", merged["limits"])
+		}
+		if limits["cpu"] != "750m" {
+			t.Errorf("limits.cpu = %v, want 750m (later element wins)", limits["cpu"])
+		}
+		if limits["memory"] != "256Mi" {
+			t.Errorf("limits.memory = %v, want 256Mi (must survive being a level below the conflicting field, not be dropped by a whole-field override)", limits["memory"])
+		}
+	})
+
+	t.Run("merge cannot reconcile a remove against a replace", func(t *testing.T) {
+		patches := []indexedPatch{
+			patchOp(t, 0, "remove", "/spec/containers/0/securityContext", nil),
+			patchOp(t, 1, "replace", "/spec/containers/0/securityContext", map[string]interface{}{"runAsNonRoot": true}),
+		}
+		if _, err := resolvePatchConflicts(kyvernov1.ForEachMutation{ConflictPolicy: kyvernov1.ConflictPolicyMerge}, patches); err == nil {
+			t.Fatal("expected an error merging a remove against a replace, got none")
+		}
+	})
+}
+
+func TestResolveSemaphore(t *testing.T) {
+	t.Run("no inherited budget and no parallelism stays sequential", func(t *testing.T) {
+		if sem := resolveSemaphore(nil, nil); sem != nil {
+			t.Errorf("expected nil semaphore, got one with cap %d", cap(sem))
+		}
+	})
+
+	t.Run("opting in creates a semaphore sized to parallelism", func(t *testing.T) {
+		n := 4
+		sem := resolveSemaphore(nil, &n)
+		if sem == nil || cap(sem) != n {
+			t.Fatalf("expected a semaphore with cap %d, got %v", n, sem)
+		}
+	})
+
+	t.Run("an inherited budget is reused as-is, never multiplied", func(t *testing.T) {
+		parent := make(chan struct{}, 2)
+		n := 8
+		if sem := resolveSemaphore(parent, &n); sem != parent {
+			t.Errorf("expected the inherited semaphore to be reused unchanged, got a different channel")
+		}
+	})
+
+	t.Run("a sibling foreach with no parallelism of its own does not inherit a budget that was never created", func(t *testing.T) {
+		// Simulates two sibling foreach entries processed by the same forEachMutator: the
+		// first opts into Parallelism (handled by its own resolveSemaphore call, not shown
+		// here), the second does not set Parallelism and is not nested, so it must not
+		// receive a semaphore just because an earlier sibling used one.
+		if sem := resolveSemaphore(nil, nil); sem != nil {
+			t.Errorf("expected nil semaphore for an opt-out foreach block, got one with cap %d", cap(sem))
+		}
+	})
+}
+
+// fakeJSONContext is a minimal engineapi.JSONContext: Query resolves a fixed set of JMESPath
+// expressions to canned results, and the bookkeeping methods are no-ops, which is enough to
+// drive forEachMutator without pulling in the real JMESPath-backed implementation.
+type fakeJSONContext struct {
+	lists map[string][]interface{}
+}
+
+func (f *fakeJSONContext) Checkpoint()                              {}
+func (f *fakeJSONContext) Restore()                                 {}
+func (f *fakeJSONContext) Reset()                                   {}
+func (f *fakeJSONContext) AddResource(map[string]interface{}) error { return nil }
+func (f *fakeJSONContext) Query(query string) (interface{}, error) {
+	return f.lists[query], nil
+}
+
+// fakePolicyContext is a minimal engineapi.PolicyContext sharing one fakeJSONContext across
+// every Copy(), since the fake's methods are all stateless/no-op and safe to call
+// concurrently.
+type fakePolicyContext struct {
+	ctx *fakeJSONContext
+}
+
+func (f *fakePolicyContext) JSONContext() engineapi.JSONContext { return f.ctx }
+func (f *fakePolicyContext) Copy() engineapi.PolicyContext      { return &fakePolicyContext{ctx: f.ctx} }
+func (f *fakePolicyContext) ExplainMutation() bool              { return false }
+
+func noopContextLoader(ctx context.Context, entries []kyvernov1.ContextEntry, jsonContext engineapi.JSONContext) error {
+	return nil
+}
+
+// TestNestedForeachSharedSemaphoreNoDeadlock is a regression test for the deadlock a reentrant
+// worker pool produces: a worker holding its own slot in sem while recursing into a nested
+// dispatch loop that acquires from that very same channel blocks forever once the pool is
+// saturated (Parallelism >= len(elements) guarantees saturation). It drives the real
+// forEachMutator/mutateElementsParallel/processElement dispatch through two levels of foreach
+// with a fake PolicyContext, so a regression in the shipped release-before-recurse logic (e.g.
+// moving the release after the nested call, or double-acquiring) would actually fail this test.
+func TestNestedForeachSharedSemaphoreNoDeadlock(t *testing.T) {
+	const elementCount = 3
+
+	outerElements := make([]interface{}, elementCount)
+	innerElements := make([]interface{}, elementCount)
+	for i := 0; i < elementCount; i++ {
+		outerElements[i] = map[string]interface{}{"name": fmt.Sprintf("outer-%d", i)}
+		innerElements[i] = map[string]interface{}{"name": fmt.Sprintf("inner-%d", i)}
+	}
+
+	innerForEach := kyvernov1.ForEachMutation{List: "nested"}
+	nestedBlob, err := json.Marshal([]kyvernov1.ForEachMutation{innerForEach})
+	if err != nil {
+		t.Fatalf("marshal nested foreach: %v", err)
+	}
+
+	parallelism := elementCount
+	outerForEach := kyvernov1.ForEachMutation{
+		List:            "items",
+		Parallelism:     &parallelism,
+		ForEachMutation: nestedBlob,
+	}
+
+	policyContext := &fakePolicyContext{ctx: &fakeJSONContext{
+		lists: map[string][]interface{}{
+			"items":  outerElements,
+			"nested": innerElements,
+		},
+	}}
+
+	f := &forEachMutator{
+		logger:        testr.New(t),
+		rule:          kyvernov1.Rule{Name: "test-rule"},
+		policyContext: policyContext,
+		foreach:       []kyvernov1.ForEachMutation{outerForEach},
+		resource:      resourceInfo{unstructured: unstructured.Unstructured{Object: map[string]interface{}{}}},
+		contextLoader: noopContextLoader,
+	}
+
+	done := make(chan *mutate.Response, 1)
+	go func() {
+		done <- f.mutateForEach(context.Background())
+	}()
+
+	select {
+	case resp := <-done:
+		if resp.Status == engineapi.RuleStatusError {
+			t.Fatalf("unexpected error response: %s", resp.Message)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("nested foreach with Parallelism >= len(elements) deadlocked")
+	}
+}
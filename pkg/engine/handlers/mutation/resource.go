@@ -0,0 +1,14 @@
+package mutation
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// resourceInfo bundles the resource a rule mutates with enough addressing information to
+// report it back as a patched target when it isn't the triggering resource itself.
+type resourceInfo struct {
+	unstructured      unstructured.Unstructured
+	parentResourceGVR schema.GroupVersionResource
+	subresource       string
+}
@@ -0,0 +1,41 @@
+// Package internal holds rule-evaluation helpers shared across handlers but not meant for
+// use outside the engine.
+package internal
+
+import (
+	"reflect"
+
+	"github.com/go-logr/logr"
+	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
+	engineapi "github.com/kyverno/kyverno/pkg/engine/api"
+)
+
+// CheckPreconditions evaluates anyAll: it passes when all of AllConditions pass (vacuously
+// true when empty) and any of AnyConditions passes (vacuously true when empty). Each
+// Condition's Key and Value are compared as given; ctx is not consulted, so no variables in
+// them are substituted.
+func CheckPreconditions(logger logr.Logger, ctx engineapi.JSONContext, anyAll kyvernov1.AnyAllConditions) (bool, error) {
+	for _, c := range anyAll.AllConditions {
+		if !conditionPasses(c) {
+			return false, nil
+		}
+	}
+
+	if len(anyAll.AnyConditions) == 0 {
+		return true, nil
+	}
+	for _, c := range anyAll.AnyConditions {
+		if conditionPasses(c) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func conditionPasses(c kyvernov1.Condition) bool {
+	equal := reflect.DeepEqual(c.Key, c.Value)
+	if c.Operator == kyvernov1.ConditionOperatorNotEquals {
+		return !equal
+	}
+	return equal
+}
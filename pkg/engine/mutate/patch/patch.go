@@ -0,0 +1,19 @@
+// Package patch sorts the JSON patch operations a mutate.foreach block produced into a
+// deterministic, applicable order.
+package patch
+
+import (
+	"sort"
+
+	"github.com/mattbaird/jsonpatch"
+)
+
+// FilterAndSortPatches returns ops sorted by JSON pointer path, so the final patch set is
+// applied in a deterministic order regardless of the order mutate.foreach elements finished
+// in (relevant once elements are evaluated concurrently).
+func FilterAndSortPatches(ops []jsonpatch.JsonPatchOperation) []jsonpatch.JsonPatchOperation {
+	sorted := make([]jsonpatch.JsonPatchOperation, len(ops))
+	copy(sorted, ops)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Path < sorted[j].Path })
+	return sorted
+}
@@ -0,0 +1,53 @@
+// Package mutate applies a single element's mutation and reports the result.
+package mutate
+
+import (
+	"github.com/go-logr/logr"
+	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
+	engineapi "github.com/kyverno/kyverno/pkg/engine/api"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Response is the outcome of mutating one element, or of merging a whole mutate.foreach
+// block's elements back together.
+type Response struct {
+	Status          engineapi.RuleStatus
+	PatchedResource unstructured.Unstructured
+	Patches         [][]byte
+	Message         string
+	ElementErrors   []engineapi.ElementError
+	PatchProvenance []engineapi.PatchOrigin
+}
+
+// NewResponse builds a Response carrying the given status, patched resource and patches.
+func NewResponse(status engineapi.RuleStatus, resource unstructured.Unstructured, patches [][]byte, message string) *Response {
+	return &Response{Status: status, PatchedResource: resource, Patches: patches, Message: message}
+}
+
+// NewErrorResponse builds an engineapi.RuleStatusError Response wrapping err.
+func NewErrorResponse(message string, err error) *Response {
+	if err != nil {
+		message = message + ": " + err.Error()
+	}
+	return &Response{Status: engineapi.RuleStatusError, Message: message}
+}
+
+// WithElementErrors attaches the elements that failed under ErrorPolicy Collect.
+func (r *Response) WithElementErrors(errs ...engineapi.ElementError) *Response {
+	r.ElementErrors = append(r.ElementErrors, errs...)
+	return r
+}
+
+// WithPatchProvenance attaches per-element patch provenance computed in explain mode.
+func (r *Response) WithPatchProvenance(origins ...engineapi.PatchOrigin) *Response {
+	r.PatchProvenance = append(r.PatchProvenance, origins...)
+	return r
+}
+
+// ForEach handles one mutate.foreach element whose patch is authored (RawPatchStrategicMerge,
+// or the rule's own patches) rather than computed. It applies no patch: it always returns a
+// RuleStatusSkip Response carrying resource unchanged and no patches. PatchStrategy
+// TwoWayMerge and nested foreach are handled by the caller before reaching here.
+func ForEach(ruleName string, foreach kyvernov1.ForEachMutation, policyContext engineapi.PolicyContext, resource unstructured.Unstructured, element interface{}, logger logr.Logger) *Response {
+	return NewResponse(engineapi.RuleStatusSkip, resource, nil, "no patch produced for this element")
+}
@@ -0,0 +1,89 @@
+// Package utils holds small helpers shared by rule handlers: evaluating JMESPath list
+// expressions, binding foreach elements into context, and applying JSON patches.
+package utils
+
+import (
+	"bytes"
+	"fmt"
+
+	jsonpatchv5 "github.com/evanphx/json-patch"
+	engineapi "github.com/kyverno/kyverno/pkg/engine/api"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// EvaluateList evaluates a JMESPath list expression (e.g. "request.object.spec.containers")
+// against ctx and returns the matched elements. A query that matches nothing returns an empty
+// slice rather than an error.
+func EvaluateList(jmesPath string, ctx engineapi.JSONContext) ([]interface{}, error) {
+	result, err := ctx.Query(jmesPath)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, nil
+	}
+	elements, ok := result.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected a list at %q, got %T", jmesPath, result)
+	}
+	return elements, nil
+}
+
+// InvertedElement reverses elements in place. mutate.foreach with a RawPatchStrategicMerge
+// applies list-type patches (e.g. prepending to an array) in reverse element order so the
+// resulting order matches the order the elements were declared in.
+func InvertedElement(elements []interface{}) {
+	for i, j := 0, len(elements)-1; i < j; i, j = i+1, j-1 {
+		elements[i], elements[j] = elements[j], elements[i]
+	}
+}
+
+// AddElementToContext binds the current mutate.foreach element and its index into
+// policyContext's JSONContext, so the rest of the rule (conditions, context entries, target
+// patches) can reference "element"/"elementIndex" via JMESPath. nesting and handlerFailed are
+// accepted but not used; the nesting level is not bound.
+func AddElementToContext(policyContext engineapi.PolicyContext, element interface{}, index, nesting int, handlerFailed *bool) error {
+	resource := map[string]interface{}{
+		"element":      element,
+		"elementIndex": index,
+	}
+	return policyContext.JSONContext().AddResource(resource)
+}
+
+// ApplyPatches applies a set of RFC 6902 JSON patch documents, each a single-operation JSON
+// object, to resource and returns the patched result.
+func ApplyPatches(resource unstructured.Unstructured, patches [][]byte) (unstructured.Unstructured, error) {
+	if len(patches) == 0 {
+		return resource, nil
+	}
+
+	resourceJSON, err := resource.MarshalJSON()
+	if err != nil {
+		return resource, fmt.Errorf("failed to marshal resource: %v", err)
+	}
+
+	combined := bytes.NewBufferString("[")
+	for i, p := range patches {
+		if i > 0 {
+			combined.WriteByte(',')
+		}
+		combined.Write(p)
+	}
+	combined.WriteByte(']')
+
+	decoded, err := jsonpatchv5.DecodePatch(combined.Bytes())
+	if err != nil {
+		return resource, fmt.Errorf("failed to decode patch: %v", err)
+	}
+
+	patchedJSON, err := decoded.Apply(resourceJSON)
+	if err != nil {
+		return resource, fmt.Errorf("failed to apply patch: %v", err)
+	}
+
+	var patched unstructured.Unstructured
+	if err := patched.UnmarshalJSON(patchedJSON); err != nil {
+		return resource, fmt.Errorf("failed to unmarshal patched resource: %v", err)
+	}
+	return patched, nil
+}
@@ -0,0 +1,50 @@
+// Package variables substitutes JMESPath variables embedded in policy-authored values.
+package variables
+
+import (
+	"strings"
+
+	"github.com/go-logr/logr"
+	engineapi "github.com/kyverno/kyverno/pkg/engine/api"
+)
+
+// SubstituteAll walks obj and replaces every string that consists entirely of one
+// "{{ ... }}" JMESPath variable with the value it evaluates to against ctx, returning the
+// substituted value. A variable embedded inside a longer string is left as written.
+func SubstituteAll(logger logr.Logger, ctx engineapi.JSONContext, obj interface{}) (interface{}, error) {
+	switch v := obj.(type) {
+	case string:
+		return substituteString(ctx, v)
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k, val := range v {
+			sub, err := SubstituteAll(logger, ctx, val)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = sub
+		}
+		return out, nil
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, val := range v {
+			sub, err := SubstituteAll(logger, ctx, val)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = sub
+		}
+		return out, nil
+	default:
+		return obj, nil
+	}
+}
+
+func substituteString(ctx engineapi.JSONContext, s string) (interface{}, error) {
+	const prefix, suffix = "{{", "}}"
+	if len(s) <= len(prefix)+len(suffix) || s[:len(prefix)] != prefix || s[len(s)-len(suffix):] != suffix {
+		return s, nil
+	}
+	query := strings.TrimSpace(s[len(prefix) : len(s)-len(suffix)])
+	return ctx.Query(query)
+}
@@ -0,0 +1,14 @@
+// Package api holds small generic (de)serialization helpers shared across the engine.
+package api
+
+import "encoding/json"
+
+// DeserializeJSONArray unmarshals raw as a JSON array of T, e.g. a nested
+// []kyvernov1.ForEachMutation stored as a serialized blob on a parent ForEachMutation.
+func DeserializeJSONArray[T any](raw []byte) ([]T, error) {
+	var out []T
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}